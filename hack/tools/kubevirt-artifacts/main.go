@@ -0,0 +1,160 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Command kubevirt-artifacts indexes the spec-<hash>-<attempt>.json manifests written by
+// reporter.StructuredKubernetesReporter and lets CI tooling filter and aggregate them, e.g. to count
+// failures per SIG without scraping Ginkgo's human-readable output.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// resourceArtifact mirrors reporter.ResourceArtifact.
+type resourceArtifact struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
+// nodeLogPaths mirrors reporter.NodeLogPaths.
+type nodeLogPaths struct {
+	VirtLauncher []string `json:"virtLauncher,omitempty"`
+	VirtHandler  string   `json:"virtHandler,omitempty"`
+}
+
+// specArtifact mirrors reporter.SpecArtifact. It is redeclared here, rather than imported, so that
+// this binary has no compile-time dependency on the tests tree.
+type specArtifact struct {
+	SpecPath       []string                `json:"specPath"`
+	Labels         []string                `json:"labels"`
+	FailureMessage string                  `json:"failureMessage,omitempty"`
+	StartTime      string                  `json:"startTime"`
+	EndTime        string                  `json:"endTime"`
+	Resources      []resourceArtifact      `json:"resources,omitempty"`
+	NodeLogPaths   map[string]nodeLogPaths `json:"nodeLogPaths,omitempty"`
+	Checksum       string                  `json:"checksum"`
+}
+
+func main() {
+	artifactsDir := flag.String("artifacts-dir", "", "directory containing spec-<hash>-<attempt>.json manifests (required)")
+	sigFilter := flag.String("sig", "", "only consider manifests labeled with this SIG (e.g. sig-network)")
+	aggregate := flag.Bool("aggregate", false, "print failure counts grouped by SIG label instead of listing manifests")
+	flag.Parse()
+
+	if *artifactsDir == "" {
+		fmt.Fprintln(os.Stderr, "-artifacts-dir is required")
+		os.Exit(2)
+	}
+
+	artifacts, err := loadArtifacts(*artifactsDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load artifacts: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *sigFilter != "" {
+		artifacts = filterBySIG(artifacts, *sigFilter)
+	}
+
+	if *aggregate {
+		printAggregateBySIG(artifacts)
+		return
+	}
+
+	printArtifacts(artifacts)
+}
+
+// loadArtifacts reads every spec-*.json manifest directly under dir.
+func loadArtifacts(dir string) ([]specArtifact, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "spec-*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var artifacts []specArtifact
+	for _, path := range matches {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		var artifact specArtifact
+		if err := json.Unmarshal(raw, &artifact); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		artifacts = append(artifacts, artifact)
+	}
+
+	return artifacts, nil
+}
+
+// filterBySIG returns only the artifacts carrying a "sig-*" label matching sig.
+func filterBySIG(artifacts []specArtifact, sig string) []specArtifact {
+	var filtered []specArtifact
+	for _, artifact := range artifacts {
+		for _, label := range artifact.Labels {
+			if label == sig {
+				filtered = append(filtered, artifact)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// sigLabelOf returns the first "sig-*" label on artifact, or "unlabeled" if it has none.
+func sigLabelOf(artifact specArtifact) string {
+	for _, label := range artifact.Labels {
+		if strings.HasPrefix(label, "sig-") {
+			return label
+		}
+	}
+	return "unlabeled"
+}
+
+func printAggregateBySIG(artifacts []specArtifact) {
+	counts := map[string]int{}
+	for _, artifact := range artifacts {
+		counts[sigLabelOf(artifact)]++
+	}
+
+	sigs := make([]string, 0, len(counts))
+	for sig := range counts {
+		sigs = append(sigs, sig)
+	}
+	sort.Strings(sigs)
+
+	for _, sig := range sigs {
+		fmt.Printf("%s\t%d\n", sig, counts[sig])
+	}
+}
+
+func printArtifacts(artifacts []specArtifact) {
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].StartTime < artifacts[j].StartTime })
+
+	for _, artifact := range artifacts {
+		fmt.Printf("%s\t%s\t%s\n", sigLabelOf(artifact), strings.Join(artifact.SpecPath, " > "), artifact.FailureMessage)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	writeManifest(t, dir, "spec-aaa-1.json", specArtifact{SpecPath: []string{"Services", "a"}, Labels: []string{"sig-network"}})
+	writeManifest(t, dir, "spec-bbb-1.json", specArtifact{SpecPath: []string{"Storage", "b"}, Labels: []string{"sig-storage"}})
+	if err := os.WriteFile(filepath.Join(dir, "ignored.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("writing ignored.json: %v", err)
+	}
+
+	artifacts, err := loadArtifacts(dir)
+	if err != nil {
+		t.Fatalf("loadArtifacts: %v", err)
+	}
+	if len(artifacts) != 2 {
+		t.Fatalf("expected 2 artifacts (ignoring non spec-*.json files), got %d", len(artifacts))
+	}
+}
+
+func TestFilterBySIG(t *testing.T) {
+	artifacts := []specArtifact{
+		{SpecPath: []string{"a"}, Labels: []string{"sig-network"}},
+		{SpecPath: []string{"b"}, Labels: []string{"sig-storage"}},
+		{SpecPath: []string{"c"}, Labels: []string{"sig-network", "slow"}},
+	}
+
+	filtered := filterBySIG(artifacts, "sig-network")
+	if len(filtered) != 2 {
+		t.Errorf("expected 2 artifacts labeled sig-network, got %d", len(filtered))
+	}
+}
+
+func TestSigLabelOf(t *testing.T) {
+	cases := []struct {
+		labels []string
+		want   string
+	}{
+		{[]string{"sig-network", "slow"}, "sig-network"},
+		{[]string{"slow", "sig-storage"}, "sig-storage"},
+		{[]string{"slow"}, "unlabeled"},
+		{nil, "unlabeled"},
+	}
+
+	for _, tc := range cases {
+		if got := sigLabelOf(specArtifact{Labels: tc.labels}); got != tc.want {
+			t.Errorf("sigLabelOf(%v) = %q, want %q", tc.labels, got, tc.want)
+		}
+	}
+}
+
+func TestPrintAggregateBySIG(t *testing.T) {
+	artifacts := []specArtifact{
+		{Labels: []string{"sig-network"}},
+		{Labels: []string{"sig-network"}},
+		{Labels: []string{"sig-storage"}},
+		{Labels: nil},
+	}
+
+	output := captureStdout(t, func() {
+		printAggregateBySIG(artifacts)
+	})
+
+	want := "sig-network\t2\nsig-storage\t1\nunlabeled\t1\n"
+	if output != want {
+		t.Errorf("printAggregateBySIG() output = %q, want %q", output, want)
+	}
+}
+
+func writeManifest(t *testing.T, dir, name string, artifact specArtifact) {
+	t.Helper()
+	raw, err := json.Marshal(artifact)
+	if err != nil {
+		t.Fatalf("marshaling manifest: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), raw, 0644); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	original := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("creating pipe: %v", err)
+	}
+	os.Stdout = w
+
+	fn()
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing pipe writer: %v", err)
+	}
+	os.Stdout = original
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, r); err != nil {
+		t.Fatalf("reading pipe: %v", err)
+	}
+	return buf.String()
+}
@@ -0,0 +1,139 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+// Package retry wraps typed k8s client calls in Gomega's Eventually, retrying on the handful of
+// API-server errors that are known to be transient (conflicts, server timeouts, throttling,
+// internal errors) instead of failing the calling spec outright.
+package retry
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	defaultPoll    = time.Second
+)
+
+func isRetriable(err error) bool {
+	return errors.IsServerTimeout(err) || errors.IsTooManyRequests(err) || errors.IsConflict(err) || errors.IsInternalError(err)
+}
+
+// CreateWithRetry retries create on the retriable API-server errors, and fails fast if the object
+// already exists.
+func CreateWithRetry[T any](ctx context.Context, create func(ctx context.Context) (T, error)) T {
+	var result T
+	Eventually(func() error {
+		var err error
+		result, err = create(ctx)
+		if err != nil && errors.IsAlreadyExists(err) {
+			StopTrying("object already exists").Wrap(err).Now()
+		}
+		if err != nil && !isRetriable(err) {
+			StopTrying("non-retriable error creating object").Wrap(err).Now()
+		}
+		return err
+	}, defaultTimeout, defaultPoll).Should(Succeed())
+
+	return result
+}
+
+// GetWithRetry retries get on the retriable API-server errors. In idempotent mode, a NotFound error
+// is treated as success and the zero value of T is returned.
+func GetWithRetry[T any](ctx context.Context, get func(ctx context.Context) (T, error), idempotent bool) (T, error) {
+	var result T
+	var getErr error
+	Eventually(func() error {
+		result, getErr = get(ctx)
+		if getErr == nil {
+			return nil
+		}
+		if idempotent && errors.IsNotFound(getErr) {
+			return nil
+		}
+		if errors.IsNotFound(getErr) || !isRetriable(getErr) {
+			StopTrying("non-retriable error getting object").Wrap(getErr).Now()
+		}
+		return getErr
+	}, defaultTimeout, defaultPoll).Should(Succeed())
+
+	if idempotent && errors.IsNotFound(getErr) {
+		var zero T
+		return zero, nil
+	}
+	return result, getErr
+}
+
+// UpdateWithRetry retries update on the retriable API-server errors. On conflict, it re-fetches the
+// object via get, re-applies mutate, and retries the update with the refreshed resource version.
+func UpdateWithRetry[T any](
+	ctx context.Context,
+	get func(ctx context.Context) (T, error),
+	update func(ctx context.Context, obj T) (T, error),
+	mutate func(obj T),
+) T {
+	var result T
+	Eventually(func() error {
+		current, err := get(ctx)
+		if err != nil {
+			if !isRetriable(err) {
+				StopTrying("non-retriable error fetching object for update").Wrap(err).Now()
+			}
+			return err
+		}
+
+		mutate(current)
+		result, err = update(ctx, current)
+		if err != nil && !isRetriable(err) {
+			StopTrying("non-retriable error updating object").Wrap(err).Now()
+		}
+		return err
+	}, defaultTimeout, defaultPoll).Should(Succeed())
+
+	return result
+}
+
+// DeleteWithRetry retries delete on the retriable API-server errors. In idempotent mode, a NotFound
+// error is treated as success.
+func DeleteWithRetry(ctx context.Context, delete func(ctx context.Context) error, idempotent bool) error {
+	var deleteErr error
+	Eventually(func() error {
+		deleteErr = delete(ctx)
+		if deleteErr == nil {
+			return nil
+		}
+		if idempotent && errors.IsNotFound(deleteErr) {
+			return nil
+		}
+		if errors.IsNotFound(deleteErr) || !isRetriable(deleteErr) {
+			StopTrying("non-retriable error deleting object").Wrap(deleteErr).Now()
+		}
+		return deleteErr
+	}, defaultTimeout, defaultPoll).Should(Succeed())
+
+	if idempotent && errors.IsNotFound(deleteErr) {
+		return nil
+	}
+	return deleteErr
+}
@@ -0,0 +1,170 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package retry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var testGroupResource = schema.GroupResource{Resource: "services"}
+
+// TestMain swaps in a fail handler that records failures instead of panicking through Ginkgo's, so
+// the StopTrying/fail-fast paths below can be asserted on like any other return value.
+func TestMain(m *testing.M) {
+	gomega.RegisterFailHandler(func(message string, callerSkip ...int) {
+		recordedFailures = append(recordedFailures, message)
+	})
+	os.Exit(m.Run())
+}
+
+var recordedFailures []string
+
+func TestIsRetriable(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"conflict":          {errors.NewConflict(testGroupResource, "name", nil), true},
+		"server timeout":    {errors.NewServerTimeout(testGroupResource, "get", 1), true},
+		"too many requests": {errors.NewTooManyRequests("throttled", 1), true},
+		"internal error":    {errors.NewInternalError(fmt.Errorf("boom")), true},
+		"not found":         {errors.NewNotFound(testGroupResource, "name"), false},
+		"already exists":    {errors.NewAlreadyExists(testGroupResource, "name"), false},
+		"unrelated error":   {errors.NewBadRequest("bad"), false},
+	}
+
+	for name, tc := range cases {
+		if got := isRetriable(tc.err); got != tc.want {
+			t.Errorf("%s: isRetriable() = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestCreateWithRetry_RetriesTransientErrorsThenSucceeds(t *testing.T) {
+	attempts := 0
+	result := CreateWithRetry(context.Background(), func(ctx context.Context) (string, error) {
+		attempts++
+		if attempts < 2 {
+			return "", errors.NewServerTimeout(testGroupResource, "create", 1)
+		}
+		return "created", nil
+	})
+
+	if result != "created" {
+		t.Errorf("CreateWithRetry() = %q, want %q", result, "created")
+	}
+	if attempts != 2 {
+		t.Errorf("expected exactly 2 attempts, got %d", attempts)
+	}
+}
+
+func TestGetWithRetry_IdempotentNotFoundReturnsZeroValue(t *testing.T) {
+	result, err := GetWithRetry(context.Background(), func(ctx context.Context) (string, error) {
+		return "", errors.NewNotFound(testGroupResource, "name")
+	}, true)
+
+	if err != nil {
+		t.Errorf("expected no error in idempotent mode, got %v", err)
+	}
+	if result != "" {
+		t.Errorf("expected the zero value, got %q", result)
+	}
+}
+
+func TestGetWithRetry_NonIdempotentNotFoundStopsTrying(t *testing.T) {
+	before := len(recordedFailures)
+	attempts := 0
+
+	_, err := GetWithRetry(context.Background(), func(ctx context.Context) (string, error) {
+		attempts++
+		return "", errors.NewNotFound(testGroupResource, "name")
+	}, false)
+
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected a NotFound error, got %v", err)
+	}
+	if attempts != 1 {
+		t.Errorf("expected fail-fast after a single attempt, got %d attempts", attempts)
+	}
+	if len(recordedFailures) == before {
+		t.Error("expected StopTrying to report a failure")
+	}
+}
+
+// counter is a stand-in for a typed k8s object: T is a pointer type, so mutate can edit it in place
+// the way a real mutate func would flip a field on a freshly re-fetched object.
+type counter struct {
+	value int
+}
+
+func TestUpdateWithRetry_RefetchesAndReappliesOnConflict(t *testing.T) {
+	updateAttempts := 0
+	result := UpdateWithRetry(
+		context.Background(),
+		func(ctx context.Context) (*counter, error) {
+			return &counter{value: 1}, nil
+		},
+		func(ctx context.Context, obj *counter) (*counter, error) {
+			updateAttempts++
+			if updateAttempts < 2 {
+				return nil, errors.NewConflict(testGroupResource, "name", nil)
+			}
+			return obj, nil
+		},
+		func(obj *counter) {
+			obj.value++
+		},
+	)
+
+	if result.value != 2 {
+		t.Errorf("UpdateWithRetry() value = %d, want %d", result.value, 2)
+	}
+	if updateAttempts != 2 {
+		t.Errorf("expected exactly 2 update attempts, got %d", updateAttempts)
+	}
+}
+
+func TestDeleteWithRetry_IdempotentNotFoundSucceeds(t *testing.T) {
+	err := DeleteWithRetry(context.Background(), func(ctx context.Context) error {
+		return errors.NewNotFound(testGroupResource, "name")
+	}, true)
+
+	if err != nil {
+		t.Errorf("expected no error in idempotent mode, got %v", err)
+	}
+}
+
+func TestDeleteWithRetry_NonIdempotentNotFoundFails(t *testing.T) {
+	err := DeleteWithRetry(context.Background(), func(ctx context.Context) error {
+		return errors.NewNotFound(testGroupResource, "name")
+	}, false)
+
+	if !errors.IsNotFound(err) {
+		t.Errorf("expected a NotFound error, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package job
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	k8sv1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const helloWorldExpectedResponse = "Hello World!"
+
+// NewHelloWorldJobUDP returns a Job whose pod sends a single UDP datagram to host:port and succeeds
+// only if it receives helloWorldExpectedResponse back within 5 seconds. Unlike TCP, a UDP
+// "connection" can be established even when nothing is listening, so success here is judged purely
+// by the payload received, not by the socket call succeeding.
+func NewHelloWorldJobUDP(host, port string) *batchv1.Job {
+	check := fmt.Sprintf(
+		`x="$(echo | nc -u -w 5 %s %s)"; echo "$x" ; if [ "$x" != "%s" ]; then echo "fail: $x" ; exit 1 ; fi`,
+		host, port, helloWorldExpectedResponse,
+	)
+	return newHelloWorldJob(check)
+}
+
+// NewHelloWorldJobSCTP returns a Job whose pod connects to host:port over SCTP and succeeds only if
+// it receives helloWorldExpectedResponse back.
+func NewHelloWorldJobSCTP(host, port string) *batchv1.Job {
+	check := fmt.Sprintf(
+		`x="$(echo | ncat --sctp -w 5 %s %s)"; echo "$x" ; if [ "$x" != "%s" ]; then echo "fail: $x" ; exit 1 ; fi`,
+		host, port, helloWorldExpectedResponse,
+	)
+	return newHelloWorldJob(check)
+}
+
+func newHelloWorldJob(shellCommand string) *batchv1.Job {
+	zero := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			GenerateName: "hello-world-job-",
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &zero,
+			Template: k8sv1.PodTemplateSpec{
+				Spec: k8sv1.PodSpec{
+					RestartPolicy: k8sv1.RestartPolicyNever,
+					Containers: []k8sv1.Container{
+						{
+							Name:    "hello-world-job",
+							Image:   "quay.io/kubevirtci/alpine-with-test-tooling",
+							Command: []string{"/bin/sh", "-c", shellCommand},
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,128 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package service
+
+import (
+	k8sv1 "k8s.io/api/core/v1"
+	k8smetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// BuildNodePortSpec returns a NodePort k8sv1.Service exposing targetPort on every node, selecting
+// VMIs via selectorKey/selectorValue.
+func BuildNodePortSpec(name string, port, targetPort int, selectorKey, selectorValue string) *k8sv1.Service {
+	svc := buildSpec(name, port, targetPort, selectorKey, selectorValue, k8sv1.IPv4Protocol)
+	svc.Spec.Type = k8sv1.ServiceTypeNodePort
+	return svc
+}
+
+// BuildNodePortIPv6Spec is the IPv6 counterpart of BuildNodePortSpec.
+func BuildNodePortIPv6Spec(name string, port, targetPort int, selectorKey, selectorValue string) *k8sv1.Service {
+	svc := buildSpec(name, port, targetPort, selectorKey, selectorValue, k8sv1.IPv6Protocol)
+	svc.Spec.Type = k8sv1.ServiceTypeNodePort
+	return svc
+}
+
+// BuildLoadBalancerSpec returns a LoadBalancer k8sv1.Service selecting VMIs via selectorKey/selectorValue.
+// Callers must wait for the cloud provider (or the cluster's LB shim) to report an ingress IP before
+// relying on external connectivity.
+func BuildLoadBalancerSpec(name string, port, targetPort int, selectorKey, selectorValue string) *k8sv1.Service {
+	svc := buildSpec(name, port, targetPort, selectorKey, selectorValue, k8sv1.IPv4Protocol)
+	svc.Spec.Type = k8sv1.ServiceTypeLoadBalancer
+	return svc
+}
+
+// BuildLoadBalancerIPv6Spec is the IPv6 counterpart of BuildLoadBalancerSpec.
+func BuildLoadBalancerIPv6Spec(name string, port, targetPort int, selectorKey, selectorValue string) *k8sv1.Service {
+	svc := buildSpec(name, port, targetPort, selectorKey, selectorValue, k8sv1.IPv6Protocol)
+	svc.Spec.Type = k8sv1.ServiceTypeLoadBalancer
+	return svc
+}
+
+// BuildExternalNameSpec returns an ExternalName k8sv1.Service that resolves name to externalName via DNS CNAME.
+// It carries no selector and no ports, matching the ExternalName service semantics.
+func BuildExternalNameSpec(name, externalName string) *k8sv1.Service {
+	return &k8sv1.Service{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: k8sv1.ServiceSpec{
+			Type:         k8sv1.ServiceTypeExternalName,
+			ExternalName: externalName,
+		},
+	}
+}
+
+// WithClientIPAffinity mutates svc to use ClientIP session affinity with the given timeout, so that
+// repeated connections from the same client are routed to the same backend.
+func WithClientIPAffinity(svc *k8sv1.Service, timeoutSeconds int32) *k8sv1.Service {
+	svc.Spec.SessionAffinity = k8sv1.ServiceAffinityClientIP
+	svc.Spec.SessionAffinityConfig = &k8sv1.SessionAffinityConfig{
+		ClientIP: &k8sv1.ClientIPConfig{
+			TimeoutSeconds: &timeoutSeconds,
+		},
+	}
+	return svc
+}
+
+// BuildUDPSpec returns a ClusterIP k8sv1.Service exposing targetPort over UDP, selecting VMIs via
+// selectorKey/selectorValue.
+func BuildUDPSpec(name string, port, targetPort int, selectorKey, selectorValue string) *k8sv1.Service {
+	return buildSpecWithProtocol(name, port, targetPort, selectorKey, selectorValue, k8sv1.IPv4Protocol, k8sv1.ProtocolUDP)
+}
+
+// BuildUDPIPv6Spec is the IPv6 counterpart of BuildUDPSpec.
+func BuildUDPIPv6Spec(name string, port, targetPort int, selectorKey, selectorValue string) *k8sv1.Service {
+	return buildSpecWithProtocol(name, port, targetPort, selectorKey, selectorValue, k8sv1.IPv6Protocol, k8sv1.ProtocolUDP)
+}
+
+// BuildSCTPSpec returns a ClusterIP k8sv1.Service exposing targetPort over SCTP, selecting VMIs via
+// selectorKey/selectorValue.
+func BuildSCTPSpec(name string, port, targetPort int, selectorKey, selectorValue string) *k8sv1.Service {
+	return buildSpecWithProtocol(name, port, targetPort, selectorKey, selectorValue, k8sv1.IPv4Protocol, k8sv1.ProtocolSCTP)
+}
+
+// BuildSCTPIPv6Spec is the IPv6 counterpart of BuildSCTPSpec.
+func BuildSCTPIPv6Spec(name string, port, targetPort int, selectorKey, selectorValue string) *k8sv1.Service {
+	return buildSpecWithProtocol(name, port, targetPort, selectorKey, selectorValue, k8sv1.IPv6Protocol, k8sv1.ProtocolSCTP)
+}
+
+func buildSpec(name string, port, targetPort int, selectorKey, selectorValue string, ipFamily k8sv1.IPFamily) *k8sv1.Service {
+	return buildSpecWithProtocol(name, port, targetPort, selectorKey, selectorValue, ipFamily, k8sv1.ProtocolTCP)
+}
+
+func buildSpecWithProtocol(name string, port, targetPort int, selectorKey, selectorValue string, ipFamily k8sv1.IPFamily, protocol k8sv1.Protocol) *k8sv1.Service {
+	return &k8sv1.Service{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: k8sv1.ServiceSpec{
+			Selector: map[string]string{selectorKey: selectorValue},
+			Ports: []k8sv1.ServicePort{
+				{
+					Protocol:   protocol,
+					Port:       int32(port),
+					TargetPort: intstr.FromInt(targetPort),
+				},
+			},
+			IPFamilies: []k8sv1.IPFamily{ipFamily},
+		},
+	}
+}
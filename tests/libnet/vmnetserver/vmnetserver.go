@@ -0,0 +1,61 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package vmnetserver
+
+import (
+	"fmt"
+
+	expect "github.com/google/goexpect"
+
+	. "github.com/onsi/gomega"
+
+	v1 "kubevirt.io/api/core/v1"
+
+	"kubevirt.io/kubevirt/tests/console"
+)
+
+// StartUDPServer starts a UDP echo loop inside the guest console, listening on port and echoing
+// back "Hello World!" to every datagram it receives.
+func StartUDPServer(vmi *v1.VirtualMachineInstance, port int, loginFn console.LoginToFunction) {
+	startLoopingServer(vmi, loginFn, fmt.Sprintf("nc -klu -p %d -e echo -e 'Hello World!'\n", port))
+}
+
+// StartSCTPServer starts an SCTP echo loop inside the guest console, listening on port and echoing
+// back "Hello World!" to every connection it accepts.
+func StartSCTPServer(vmi *v1.VirtualMachineInstance, port int, loginFn console.LoginToFunction) {
+	startLoopingServer(vmi, loginFn, fmt.Sprintf("socat SCTP-LISTEN:%d,reuseaddr,fork SYSTEM:'echo Hello World!'\n", port))
+}
+
+// StartTCPServerWithIdentity starts a TCP echo loop inside the guest console, listening on port and
+// echoing back identity, rather than StartTCPServer's canned "Hello World!" reply, to every
+// connection it accepts. This lets a test tell which of several otherwise-identical backends
+// answered a given connection, e.g. when asserting session affinity.
+func StartTCPServerWithIdentity(vmi *v1.VirtualMachineInstance, port int, loginFn console.LoginToFunction, identity string) {
+	startLoopingServer(vmi, loginFn, fmt.Sprintf("nc -kl -p %d -e echo -e '%s'\n", port, identity))
+}
+
+func startLoopingServer(vmi *v1.VirtualMachineInstance, loginFn console.LoginToFunction, command string) {
+	Expect(loginFn(vmi)).To(Succeed())
+
+	Expect(console.SafeExpectBatch(vmi, []expect.Batcher{
+		&expect.BSnd{S: fmt.Sprintf("screen -d -m sh -c \"while true; do %s done\"\n", command)},
+		&expect.BExp{R: console.PromptExpression},
+	}, 60)).To(Succeed())
+}
@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"kubevirt.io/kubevirt/tests/framework/kubevirt"
@@ -41,6 +42,7 @@ import (
 	"kubevirt.io/client-go/kubecli"
 
 	"kubevirt.io/kubevirt/tests/console"
+	"kubevirt.io/kubevirt/tests/framework/retry"
 	"kubevirt.io/kubevirt/tests/libnet"
 	"kubevirt.io/kubevirt/tests/libnet/job"
 	netservice "kubevirt.io/kubevirt/tests/libnet/service"
@@ -56,6 +58,16 @@ const (
 
 	jobSuccessRetry = 3
 	jobFailureRetry = 0
+
+	nodePortServiceName        = "nodeportservice"
+	loadBalancerServiceName    = "loadbalancerservice"
+	externalNameServiceName    = "externalnameservice"
+	sessionAffinityServiceName = "sessionaffinityservice"
+
+	loadBalancerIngressTimeout = 3 * time.Minute
+	sessionAffinityTimeout     = int32(120)
+	sessionAffinityProbes      = 10
+	sessionAffinityProbePort   = 1501
 )
 
 var _ = SIGDescribe("Services", func() {
@@ -78,7 +90,9 @@ var _ = SIGDescribe("Services", func() {
 
 	cleanupVMI := func(virtClient kubecli.KubevirtClient, vmi *v1.VirtualMachineInstance) {
 		By("Deleting the VMI")
-		Expect(virtClient.VirtualMachineInstance(util.NamespaceTestDefault).Delete(context.Background(), vmi.GetName(), &k8smetav1.DeleteOptions{})).To(Succeed())
+		Expect(retry.DeleteWithRetry(context.Background(), func(ctx context.Context) error {
+			return virtClient.VirtualMachineInstance(util.NamespaceTestDefault).Delete(ctx, vmi.GetName(), &k8smetav1.DeleteOptions{})
+		}, false)).To(Succeed())
 
 		By("Waiting for the VMI to be gone")
 		Eventually(func() error {
@@ -88,7 +102,9 @@ var _ = SIGDescribe("Services", func() {
 	}
 
 	cleanupService := func(namespace string, serviceName string) error {
-		return virtClient.CoreV1().Services(namespace).Delete(context.Background(), serviceName, k8smetav1.DeleteOptions{})
+		return retry.DeleteWithRetry(context.Background(), func(ctx context.Context) error {
+			return virtClient.CoreV1().Services(namespace).Delete(ctx, serviceName, k8smetav1.DeleteOptions{})
+		}, true)
 	}
 
 	BeforeEach(func() {
@@ -136,39 +152,30 @@ var _ = SIGDescribe("Services", func() {
 
 			BeforeEach(func() {
 				service := netservice.BuildSpec(serviceName, servicePort, servicePort, selectorLabelKey, selectorLabelValue)
-				serv, err := virtClient.CoreV1().Services(inboundVMI.Namespace).Create(context.Background(), service, k8smetav1.CreateOptions{})
-				Expect(err).ToNot(HaveOccurred())
+				serv := retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*k8sv1.Service, error) {
+					return virtClient.CoreV1().Services(inboundVMI.Namespace).Create(ctx, service, k8smetav1.CreateOptions{})
+				})
 				DeferCleanup(func() {
-					err := virtClient.CoreV1().Services(serv.Namespace).Delete(context.Background(), serv.Name, k8smetav1.DeleteOptions{})
-					Expect(err).To(SatisfyAny(
-						Not(HaveOccurred()),
-						MatchError(errors.IsNotFound, "does not exist"),
-					), cleaningK8sv1ServiceShouldSucceed)
+					Expect(cleanupService(serv.Namespace, serv.Name)).To(Succeed(), cleaningK8sv1ServiceShouldSucceed)
 				})
 			})
 
 			It("[test_id:1547] should be able to reach the vmi based on labels specified on the vmi", func() {
-				tcpJob, err := createServiceConnectivityJob(serviceName, inboundVMI.Namespace, servicePort, jobSuccessRetry)
-				Expect(err).NotTo(HaveOccurred())
+				tcpJob := createServiceConnectivityJob(k8sv1.ProtocolTCP, serviceName, inboundVMI.Namespace, servicePort, jobSuccessRetry)
 				DeferCleanup(func() {
-					Expect(virtClient.BatchV1().Jobs(tcpJob.Namespace).Delete(context.Background(), tcpJob.Name, k8smetav1.DeleteOptions{})).To(Succeed())
+					Expect(cleanupJob(virtClient, tcpJob)).To(Succeed())
 				})
 
 				Expect(job.WaitForJobToSucceed(tcpJob, 90*time.Second)).To(Succeed(), expectConnectivityToExposedService)
 			})
 
 			It("[test_id:1548] should fail to reach the vmi if an invalid servicename is used", func() {
-				tcpJob, err := createServiceConnectivityJob("wrongservice", inboundVMI.Namespace, servicePort, jobFailureRetry)
-				Expect(err).NotTo(HaveOccurred())
+				tcpJob := createServiceConnectivityJob(k8sv1.ProtocolTCP, "wrongservice", inboundVMI.Namespace, servicePort, jobFailureRetry)
 				DeferCleanup(func() {
-					err := virtClient.BatchV1().Jobs(tcpJob.Namespace).Delete(context.Background(), tcpJob.Name, k8smetav1.DeleteOptions{})
-					Expect(err).To(SatisfyAny(
-						Not(HaveOccurred()),
-						MatchError(errors.IsNotFound, "does not exist"),
-					))
+					Expect(cleanupJob(virtClient, tcpJob)).To(Succeed())
 				})
 
-				err = job.WaitForJobToFail(tcpJob, 90*time.Second)
+				err := job.WaitForJobToFail(tcpJob, 90*time.Second)
 				Expect(err).NotTo(HaveOccurred(), "connectivity is *not* expected, since there isn't an exposed service")
 			})
 		})
@@ -179,30 +186,37 @@ var _ = SIGDescribe("Services", func() {
 				serviceName = inboundVMI.Spec.Subdomain
 
 				service := netservice.BuildHeadlessSpec(serviceName, servicePort, servicePort, selectorLabelKey, selectorLabelValue)
-				_, err := virtClient.CoreV1().Services(inboundVMI.Namespace).Create(context.Background(), service, k8smetav1.CreateOptions{})
-				Expect(err).ToNot(HaveOccurred())
+				retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*k8sv1.Service, error) {
+					return virtClient.CoreV1().Services(inboundVMI.Namespace).Create(ctx, service, k8smetav1.CreateOptions{})
+				})
 			})
 
 			AfterEach(func() {
-				Expect(virtClient.CoreV1().Services(inboundVMI.Namespace).Delete(context.Background(), serviceName, k8smetav1.DeleteOptions{})).To(Succeed())
+				Expect(cleanupService(inboundVMI.Namespace, serviceName)).To(Succeed())
 			})
 
 			It("[test_id:1549]should be able to reach the vmi via its unique fully qualified domain name", func() {
-				var err error
 				serviceHostnameWithSubdomain := fmt.Sprintf("%s.%s", inboundVMI.Spec.Hostname, inboundVMI.Spec.Subdomain)
 
-				tcpJob, err := createServiceConnectivityJob(serviceHostnameWithSubdomain, inboundVMI.Namespace, servicePort, jobSuccessRetry)
-				Expect(err).NotTo(HaveOccurred())
+				tcpJob := createServiceConnectivityJob(k8sv1.ProtocolTCP, serviceHostnameWithSubdomain, inboundVMI.Namespace, servicePort, jobSuccessRetry)
 				DeferCleanup(func() {
-					Expect(virtClient.BatchV1().Jobs(tcpJob.Namespace).Delete(context.Background(), tcpJob.Name, k8smetav1.DeleteOptions{})).To(
-						Succeed(),
-						cleaningK8sv1JobShouldSucceed,
-					)
+					Expect(cleanupJob(virtClient, tcpJob)).To(Succeed(), cleaningK8sv1JobShouldSucceed)
 				})
 
 				Expect(job.WaitForJobToSucceed(tcpJob, 90*time.Second)).To(Succeed(), expectConnectivityToExposedService)
 			})
 		})
+
+		Context("with NodePort, LoadBalancer, ExternalName and session-affinity services", func() {
+			serviceTaxonomyTests(
+				func() kubecli.KubevirtClient { return virtClient },
+				func() *v1.VirtualMachineInstance { return inboundVMI },
+				createReadyVMIWithBridgeBindingAndExposedService,
+				console.LoginToCirros,
+				selectorLabelKey, selectorLabelValue, servicePort,
+				cleanupService, cleanupVMI,
+			)
+		})
 	})
 
 	Context("Masquerade interface binding", func() {
@@ -229,6 +243,8 @@ var _ = SIGDescribe("Services", func() {
 
 			inboundVMI = createReadyVMIWithMasqueradeBindingAndExposedService(hostname, subdomain)
 			vmnetserver.StartTCPServer(inboundVMI, servicePort, console.LoginToFedora)
+			vmnetserver.StartUDPServer(inboundVMI, servicePort, console.LoginToFedora)
+			vmnetserver.StartSCTPServer(inboundVMI, servicePort, console.LoginToFedora)
 		})
 
 		AfterEach(func() {
@@ -243,64 +259,432 @@ var _ = SIGDescribe("Services", func() {
 				Expect(cleanupService(inboundVMI.GetNamespace(), service.Name)).To(Succeed(), cleaningK8sv1ServiceShouldSucceed)
 			})
 
-			DescribeTable("[Conformance] should be able to reach the vmi based on labels specified on the vmi", func(ipFamily k8sv1.IPFamily) {
-				serviceName := "myservice"
+			DescribeTable("[Conformance] should be able to reach the vmi based on labels specified on the vmi", func(ipFamily k8sv1.IPFamily, protocol k8sv1.Protocol) {
+				libnet.SkipWhenClusterNotSupportIPFamily(ipFamily)
+				if protocol == k8sv1.ProtocolSCTP {
+					libnet.SkipWhenClusterNotSupportsSCTP()
+				}
+
+				serviceName := fmt.Sprintf("myservice-%s", strings.ToLower(string(protocol)))
 				By("setting up resources to expose the VMI via a service", func() {
-					libnet.SkipWhenClusterNotSupportIPFamily(ipFamily)
 					if ipFamily == k8sv1.IPv6Protocol {
 						serviceName += "v6"
-						service = netservice.BuildIPv6Spec(serviceName, servicePort, servicePort, selectorLabelKey, selectorLabelValue)
-					} else {
-						service = netservice.BuildSpec(serviceName, servicePort, servicePort, selectorLabelKey, selectorLabelValue)
 					}
+					service = buildProtocolSpec(serviceName, servicePort, selectorLabelKey, selectorLabelValue, ipFamily, protocol)
 
-					_, err := virtClient.CoreV1().Services(inboundVMI.Namespace).Create(context.Background(), service, k8smetav1.CreateOptions{})
-					Expect(err).NotTo(HaveOccurred(), "the k8sv1.Service entity should have been created.")
+					retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*k8sv1.Service, error) {
+						return virtClient.CoreV1().Services(inboundVMI.Namespace).Create(ctx, service, k8smetav1.CreateOptions{})
+					})
 				})
 
 				By("checking connectivity the exposed service")
-				tcpJob, err := createServiceConnectivityJob(serviceName, inboundVMI.Namespace, servicePort, jobSuccessRetry)
-				Expect(err).NotTo(HaveOccurred())
+				connJob := createServiceConnectivityJob(protocol, serviceName, inboundVMI.Namespace, servicePort, jobSuccessRetry)
 				DeferCleanup(func() {
-					err := virtClient.BatchV1().Jobs(tcpJob.Namespace).Delete(context.Background(), tcpJob.Name, k8smetav1.DeleteOptions{})
-					Expect(err).To(SatisfyAny(
-						Not(HaveOccurred()),
-						MatchError(errors.IsNotFound, "does not exist"),
-					), cleaningK8sv1JobShouldSucceed)
+					Expect(cleanupJob(virtClient, connJob)).To(Succeed(), cleaningK8sv1JobShouldSucceed)
 				})
 
-				Expect(job.WaitForJobToSucceed(tcpJob, 90*time.Second)).To(Succeed(), expectConnectivityToExposedService)
+				Expect(job.WaitForJobToSucceed(connJob, 90*time.Second)).To(Succeed(), expectConnectivityToExposedService)
 			},
-				Entry("when the service is exposed by an IPv4 address.", k8sv1.IPv4Protocol),
-				Entry("when the service is exposed by an IPv6 address.", k8sv1.IPv6Protocol),
+				Entry("when the service is exposed by an IPv4 address, over TCP.", k8sv1.IPv4Protocol, k8sv1.ProtocolTCP),
+				Entry("when the service is exposed by an IPv6 address, over TCP.", k8sv1.IPv6Protocol, k8sv1.ProtocolTCP),
+				Entry("when the service is exposed by an IPv4 address, over UDP.", k8sv1.IPv4Protocol, k8sv1.ProtocolUDP),
+				Entry("when the service is exposed by an IPv6 address, over UDP.", k8sv1.IPv6Protocol, k8sv1.ProtocolUDP),
+				Entry("when the service is exposed by an IPv4 address, over SCTP.", k8sv1.IPv4Protocol, k8sv1.ProtocolSCTP),
+				Entry("when the service is exposed by an IPv6 address, over SCTP.", k8sv1.IPv6Protocol, k8sv1.ProtocolSCTP),
 			)
 		})
 
 		Context("*without* a service matching the vmi exposed", func() {
 			It("should fail to reach the vmi", func() {
-				tcpJob, err := createServiceConnectivityJob("missingservice", inboundVMI.Namespace, servicePort, jobFailureRetry)
-				Expect(err).NotTo(HaveOccurred())
+				tcpJob := createServiceConnectivityJob(k8sv1.ProtocolTCP, "missingservice", inboundVMI.Namespace, servicePort, jobFailureRetry)
 
 				DeferCleanup(func() {
-					err := virtClient.BatchV1().Jobs(tcpJob.Namespace).Delete(context.Background(), tcpJob.Name, k8smetav1.DeleteOptions{})
-					Expect(err).To(SatisfyAny(
-						Not(HaveOccurred()),
-						MatchError(errors.IsNotFound, "does not exist"),
-					))
+					Expect(cleanupJob(virtClient, tcpJob)).To(Succeed())
 				})
 
-				err = job.WaitForJobToFail(tcpJob, 90*time.Second)
+				err := job.WaitForJobToFail(tcpJob, 90*time.Second)
 				Expect(err).NotTo(HaveOccurred(), "connectivity is *not* expected, since there isn't an exposed service")
 			})
 		})
+
+		Context("with NodePort, LoadBalancer, ExternalName and session-affinity services", func() {
+			serviceTaxonomyTests(
+				func() kubecli.KubevirtClient { return virtClient },
+				func() *v1.VirtualMachineInstance { return inboundVMI },
+				createReadyVMIWithMasqueradeBindingAndExposedService,
+				console.LoginToFedora,
+				selectorLabelKey, selectorLabelValue, servicePort,
+				cleanupService, cleanupVMI,
+			)
+		})
 	})
 })
 
-func createServiceConnectivityJob(serviceName, namespace string, servicePort int, retries int32) (*batchv1.Job, error) {
+// serviceTaxonomyTests registers the NodePort, LoadBalancer, ExternalName and session-affinity
+// specs shared by the bridge and masquerade binding contexts above. getVirtClient and
+// getInboundVMI defer to the enclosing Context's BeforeEach-populated variables, while
+// createSecondVMI lets each binding spin up a second, identically-selected, VMI for the
+// session-affinity scenario.
+func serviceTaxonomyTests(
+	getVirtClient func() kubecli.KubevirtClient,
+	getInboundVMI func() *v1.VirtualMachineInstance,
+	createSecondVMI func(hostname, subdomain string) *v1.VirtualMachineInstance,
+	loginFn console.LoginToFunction,
+	selectorLabelKey, selectorLabelValue string,
+	servicePort int,
+	cleanupService func(namespace, serviceName string) error,
+	cleanupVMI func(virtClient kubecli.KubevirtClient, vmi *v1.VirtualMachineInstance),
+) {
+	Context("NodePort service", func() {
+		var svc *k8sv1.Service
+
+		AfterEach(func() {
+			if svc == nil {
+				return
+			}
+			Expect(cleanupService(getInboundVMI().Namespace, svc.Name)).To(Succeed(), cleaningK8sv1ServiceShouldSucceed)
+		})
+
+		DescribeTable("[test_id:9901] should reach the vmi via a worker node's IP and the allocated node port", func(ipFamily k8sv1.IPFamily) {
+			libnet.SkipWhenClusterNotSupportIPFamily(ipFamily)
+			virtClient := getVirtClient()
+			vmi := getInboundVMI()
+
+			if ipFamily == k8sv1.IPv6Protocol {
+				svc = netservice.BuildNodePortIPv6Spec(nodePortServiceName, servicePort, servicePort, selectorLabelKey, selectorLabelValue)
+			} else {
+				svc = netservice.BuildNodePortSpec(nodePortServiceName, servicePort, servicePort, selectorLabelKey, selectorLabelValue)
+			}
+			svc = retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*k8sv1.Service, error) {
+				return virtClient.CoreV1().Services(vmi.Namespace).Create(ctx, svc, k8smetav1.CreateOptions{})
+			})
+
+			nodePort, err := nodePortOf(svc)
+			Expect(err).NotTo(HaveOccurred())
+
+			nodeIP, err := nodeIPForFamily(virtClient, ipFamily)
+			Expect(err).NotTo(HaveOccurred(), "a worker node address of the expected IP family must be available")
+
+			tcpJob := createHostPortConnectivityJob(nodeIP, nodePort, jobSuccessRetry)
+			DeferCleanup(func() {
+				Expect(cleanupJob(virtClient, tcpJob)).To(Succeed())
+			})
+
+			Expect(job.WaitForJobToSucceed(tcpJob, 90*time.Second)).To(Succeed(), expectConnectivityToExposedService)
+		},
+			Entry("when the node is addressed by an IPv4 address.", k8sv1.IPv4Protocol),
+			Entry("when the node is addressed by an IPv6 address.", k8sv1.IPv6Protocol),
+		)
+	})
+
+	Context("LoadBalancer service", func() {
+		var svc *k8sv1.Service
+
+		AfterEach(func() {
+			if svc == nil {
+				return
+			}
+			Expect(cleanupService(getInboundVMI().Namespace, svc.Name)).To(Succeed(), cleaningK8sv1ServiceShouldSucceed)
+		})
+
+		DescribeTable("[test_id:9902] should reach the vmi via the external VIP reported by the cloud provider", func(ipFamily k8sv1.IPFamily) {
+			libnet.SkipWhenClusterNotSupportIPFamily(ipFamily)
+			virtClient := getVirtClient()
+			vmi := getInboundVMI()
+
+			if ipFamily == k8sv1.IPv6Protocol {
+				svc = netservice.BuildLoadBalancerIPv6Spec(loadBalancerServiceName, servicePort, servicePort, selectorLabelKey, selectorLabelValue)
+			} else {
+				svc = netservice.BuildLoadBalancerSpec(loadBalancerServiceName, servicePort, servicePort, selectorLabelKey, selectorLabelValue)
+			}
+			svc = retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*k8sv1.Service, error) {
+				return virtClient.CoreV1().Services(vmi.Namespace).Create(ctx, svc, k8smetav1.CreateOptions{})
+			})
+
+			ingressIP, err := waitForIngressIP(virtClient, svc, loadBalancerIngressTimeout)
+			if err != nil {
+				Skip("the cluster did not report a LoadBalancer ingress IP within the given timeout")
+			}
+
+			tcpJob := createHostPortConnectivityJob(ingressIP, int32(servicePort), jobSuccessRetry)
+			DeferCleanup(func() {
+				Expect(cleanupJob(virtClient, tcpJob)).To(Succeed())
+			})
+
+			Expect(job.WaitForJobToSucceed(tcpJob, 90*time.Second)).To(Succeed(), expectConnectivityToExposedService)
+		},
+			Entry("when the VIP is an IPv4 address.", k8sv1.IPv4Protocol),
+			Entry("when the VIP is an IPv6 address.", k8sv1.IPv6Protocol),
+		)
+	})
+
+	Context("ExternalName service", func() {
+		It("[test_id:9903] should resolve to the configured CNAME target", func() {
+			virtClient := getVirtClient()
+			vmi := getInboundVMI()
+
+			governingServiceName := vmi.Spec.Subdomain
+			governingService := netservice.BuildHeadlessSpec(governingServiceName, servicePort, servicePort, selectorLabelKey, selectorLabelValue)
+			retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*k8sv1.Service, error) {
+				return virtClient.CoreV1().Services(vmi.Namespace).Create(ctx, governingService, k8smetav1.CreateOptions{})
+			})
+			DeferCleanup(func() {
+				Expect(cleanupService(vmi.Namespace, governingServiceName)).To(Succeed(), cleaningK8sv1ServiceShouldSucceed)
+			})
+
+			target := fmt.Sprintf("%s.%s.svc.cluster.local", vmi.Spec.Subdomain, vmi.Namespace)
+			spec := netservice.BuildExternalNameSpec(externalNameServiceName, target)
+			svc := retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*k8sv1.Service, error) {
+				return virtClient.CoreV1().Services(vmi.Namespace).Create(ctx, spec, k8smetav1.CreateOptions{})
+			})
+			DeferCleanup(func() {
+				Expect(cleanupService(vmi.Namespace, svc.Name)).To(Succeed(), cleaningK8sv1ServiceShouldSucceed)
+			})
+
+			lookupJob := createDNSCNAMELookupJob(fmt.Sprintf("%s.%s", svc.Name, vmi.Namespace), target)
+			DeferCleanup(func() {
+				Expect(cleanupJob(virtClient, lookupJob)).To(Succeed())
+			})
+
+			Expect(job.WaitForJobToSucceed(lookupJob, 90*time.Second)).To(Succeed(), "the ExternalName service is expected to resolve to its target")
+		})
+	})
+
+	Context("session affinity", func() {
+		var secondVMI *v1.VirtualMachineInstance
+		var svc *k8sv1.Service
+
+		BeforeEach(func() {
+			vmi := getInboundVMI()
+			vmnetserver.StartTCPServerWithIdentity(vmi, sessionAffinityProbePort, loginFn, vmi.Name)
+
+			secondVMI = createSecondVMI("inbound2", "vmi2")
+			vmnetserver.StartTCPServerWithIdentity(secondVMI, sessionAffinityProbePort, loginFn, secondVMI.Name)
+		})
+
+		AfterEach(func() {
+			cleanupVMI(getVirtClient(), secondVMI)
+			Expect(cleanupService(getInboundVMI().Namespace, svc.Name)).To(Succeed(), cleaningK8sv1ServiceShouldSucceed)
+		})
+
+		It("[test_id:9904] should consistently route a single client to the same VMI replica", func() {
+			virtClient := getVirtClient()
+			vmi := getInboundVMI()
+
+			// The two backends echo their own VMI name rather than a shared canned response, so the
+			// probe job can actually tell whether affinity held or the client was round-robined.
+			spec := netservice.WithClientIPAffinity(
+				netservice.BuildSpec(sessionAffinityServiceName, servicePort, sessionAffinityProbePort, selectorLabelKey, selectorLabelValue),
+				sessionAffinityTimeout,
+			)
+			svc = retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*k8sv1.Service, error) {
+				return virtClient.CoreV1().Services(vmi.Namespace).Create(ctx, spec, k8smetav1.CreateOptions{})
+			})
+
+			affinityJob := createSessionAffinityProbeJob(svc.Name, vmi.Namespace, servicePort, sessionAffinityProbes)
+			DeferCleanup(func() {
+				Expect(cleanupJob(virtClient, affinityJob)).To(Succeed())
+			})
+
+			Expect(job.WaitForJobToSucceed(affinityJob, 2*time.Minute)).To(Succeed(),
+				"every one of the repeated connections from the probe pod is expected to land on the same VMI replica")
+		})
+	})
+}
+
+func createServiceConnectivityJob(protocol k8sv1.Protocol, serviceName, namespace string, servicePort int, retries int32) *batchv1.Job {
 	serviceFQDN := fmt.Sprintf("%s.%s", serviceName, namespace)
 
-	By(fmt.Sprintf("starting a job which tries to reach the vmi via service %s, on port %d", serviceFQDN, servicePort))
-	tcpJob := job.NewHelloWorldJobTCP(serviceFQDN, strconv.Itoa(servicePort))
+	By(fmt.Sprintf("starting a job which tries to reach the vmi via service %s, on port %d, over %s", serviceFQDN, servicePort, protocol))
+	connJob := newHelloWorldJobForProtocol(protocol, serviceFQDN, strconv.Itoa(servicePort))
+	connJob.Spec.BackoffLimit = &retries
+	return retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*batchv1.Job, error) {
+		return kubevirt.Client().BatchV1().Jobs(namespace).Create(ctx, connJob, k8smetav1.CreateOptions{})
+	})
+}
+
+// newHelloWorldJobForProtocol dispatches to the job package's per-protocol constructor.
+func newHelloWorldJobForProtocol(protocol k8sv1.Protocol, host, port string) *batchv1.Job {
+	switch protocol {
+	case k8sv1.ProtocolUDP:
+		return job.NewHelloWorldJobUDP(host, port)
+	case k8sv1.ProtocolSCTP:
+		return job.NewHelloWorldJobSCTP(host, port)
+	default:
+		return job.NewHelloWorldJobTCP(host, port)
+	}
+}
+
+// buildProtocolSpec builds the k8sv1.Service spec matching ipFamily and protocol, dispatching to the
+// corresponding netservice.Build*Spec constructor.
+func buildProtocolSpec(name string, port int, selectorKey, selectorValue string, ipFamily k8sv1.IPFamily, protocol k8sv1.Protocol) *k8sv1.Service {
+	isIPv6 := ipFamily == k8sv1.IPv6Protocol
+	switch protocol {
+	case k8sv1.ProtocolUDP:
+		if isIPv6 {
+			return netservice.BuildUDPIPv6Spec(name, port, port, selectorKey, selectorValue)
+		}
+		return netservice.BuildUDPSpec(name, port, port, selectorKey, selectorValue)
+	case k8sv1.ProtocolSCTP:
+		if isIPv6 {
+			return netservice.BuildSCTPIPv6Spec(name, port, port, selectorKey, selectorValue)
+		}
+		return netservice.BuildSCTPSpec(name, port, port, selectorKey, selectorValue)
+	default:
+		if isIPv6 {
+			return netservice.BuildIPv6Spec(name, port, port, selectorKey, selectorValue)
+		}
+		return netservice.BuildSpec(name, port, port, selectorKey, selectorValue)
+	}
+}
+
+// createHostPortConnectivityJob probes host:port directly, bypassing Service DNS resolution. It is
+// used for NodePort and LoadBalancer services, which are reached via a node or VIP address rather
+// than the Service's own FQDN.
+func createHostPortConnectivityJob(host string, port int32, retries int32) *batchv1.Job {
+	By(fmt.Sprintf("starting a job which tries to reach %s on port %d", host, port))
+	tcpJob := job.NewHelloWorldJobTCP(host, strconv.Itoa(int(port)))
 	tcpJob.Spec.BackoffLimit = &retries
-	return kubevirt.Client().BatchV1().Jobs(namespace).Create(context.Background(), tcpJob, k8smetav1.CreateOptions{})
+	return retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*batchv1.Job, error) {
+		return kubevirt.Client().BatchV1().Jobs(util.NamespaceTestDefault).Create(ctx, tcpJob, k8smetav1.CreateOptions{})
+	})
+}
+
+// createDNSCNAMELookupJob starts a job that resolves name and asserts it comes back as target,
+// as expected of an ExternalName service.
+func createDNSCNAMELookupJob(name, target string) *batchv1.Job {
+	By(fmt.Sprintf("starting a job which resolves %s and expects the CNAME target %s", name, target))
+	lookupJob := newShellJob(fmt.Sprintf("getent hosts %s | grep -q %s", name, target))
+	return retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*batchv1.Job, error) {
+		return kubevirt.Client().BatchV1().Jobs(util.NamespaceTestDefault).Create(ctx, lookupJob, k8smetav1.CreateOptions{})
+	})
+}
+
+// createSessionAffinityProbeJob fires `probes` parallel connections to serviceName from a single
+// pod and fails unless every response is identical, i.e. unless every connection landed on the same
+// VMI replica.
+func createSessionAffinityProbeJob(serviceName, namespace string, port int, probes int) *batchv1.Job {
+	serviceFQDN := fmt.Sprintf("%s.%s", serviceName, namespace)
+
+	By(fmt.Sprintf("starting a job which fires %d parallel connections at %s:%d and expects a single consistent replica to answer", probes, serviceFQDN, port))
+	affinityJob := newShellJob(fmt.Sprintf(
+		`set -e; for i in $(seq 1 %d); do (echo "Hello World!" | nc -w 3 %s %d > /tmp/response_$i) & done; wait; test $(sort -u /tmp/response_* | wc -l) -eq 1`,
+		probes, serviceFQDN, port,
+	))
+	return retry.CreateWithRetry(context.Background(), func(ctx context.Context) (*batchv1.Job, error) {
+		return kubevirt.Client().BatchV1().Jobs(namespace).Create(ctx, affinityJob, k8smetav1.CreateOptions{})
+	})
+}
+
+// cleanupJob deletes j, tolerating it already being gone.
+func cleanupJob(virtClient kubecli.KubevirtClient, j *batchv1.Job) error {
+	return retry.DeleteWithRetry(context.Background(), func(ctx context.Context) error {
+		return virtClient.BatchV1().Jobs(j.Namespace).Delete(ctx, j.Name, k8smetav1.DeleteOptions{})
+	}, true)
+}
+
+// newShellJob returns a single-container, non-retrying Job that runs command to completion via
+// /bin/sh -c, succeeding or failing based on the command's exit code.
+func newShellJob(command string) *batchv1.Job {
+	zero := int32(0)
+	return &batchv1.Job{
+		ObjectMeta: k8smetav1.ObjectMeta{
+			GenerateName: "service-probe-",
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &zero,
+			Template: k8sv1.PodTemplateSpec{
+				Spec: k8sv1.PodSpec{
+					RestartPolicy: k8sv1.RestartPolicyNever,
+					Containers: []k8sv1.Container{
+						{
+							Name:    "probe",
+							Image:   "quay.io/kubevirtci/alpine-with-test-tooling",
+							Command: []string{"/bin/sh", "-c", command},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// nodePortOf returns the allocated node port of svc's first port, failing the caller's expectations
+// if svc was not created as a NodePort (or LoadBalancer) service.
+func nodePortOf(svc *k8sv1.Service) (int32, error) {
+	if len(svc.Spec.Ports) == 0 {
+		return 0, fmt.Errorf("service %s/%s has no ports", svc.Namespace, svc.Name)
+	}
+	nodePort := svc.Spec.Ports[0].NodePort
+	if nodePort == 0 {
+		return 0, fmt.Errorf("service %s/%s was not allocated a node port", svc.Namespace, svc.Name)
+	}
+	return nodePort, nil
+}
+
+// nodeIPForFamily returns the address of the first schedulable, Ready worker node matching ipFamily.
+func nodeIPForFamily(virtClient kubecli.KubevirtClient, ipFamily k8sv1.IPFamily) (string, error) {
+	nodes, err := virtClient.CoreV1().Nodes().List(context.Background(), k8smetav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	wantIPv6 := ipFamily == k8sv1.IPv6Protocol
+	for _, node := range nodes.Items {
+		if !isSchedulableAndReady(node) {
+			continue
+		}
+		for _, addr := range node.Status.Addresses {
+			if addr.Type != k8sv1.NodeInternalIP && addr.Type != k8sv1.NodeExternalIP {
+				continue
+			}
+			if strings.Contains(addr.Address, ":") == wantIPv6 {
+				return addr.Address, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no node address of the requested IP family was found")
+}
+
+// isSchedulableAndReady reports whether node is eligible to receive new workloads: not cordoned,
+// carrying no NoSchedule/NoExecute taint, and reporting NodeReady=True.
+func isSchedulableAndReady(node k8sv1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == k8sv1.TaintEffectNoSchedule || taint.Effect == k8sv1.TaintEffectNoExecute {
+			return false
+		}
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == k8sv1.NodeReady {
+			return condition.Status == k8sv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// waitForIngressIP polls svc until the cloud provider (or the cluster's LoadBalancer shim) reports
+// an ingress IP, or returns an error once timeout elapses. Callers are expected to Skip rather than
+// fail the spec when no ingress IP ever materializes, since that reflects the cluster's capabilities
+// rather than a product bug.
+func waitForIngressIP(virtClient kubecli.KubevirtClient, svc *k8sv1.Service, timeout time.Duration) (string, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		current, err := retry.GetWithRetry(context.Background(), func(ctx context.Context) (*k8sv1.Service, error) {
+			return virtClient.CoreV1().Services(svc.Namespace).Get(ctx, svc.Name, k8smetav1.GetOptions{})
+		}, false)
+		if err != nil {
+			return "", err
+		}
+		if ingresses := current.Status.LoadBalancer.Ingress; len(ingresses) > 0 && ingresses[0].IP != "" {
+			return ingresses[0].IP, nil
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("service %s/%s was not allocated a LoadBalancer ingress IP within %s", svc.Namespace, svc.Name, timeout)
+		}
+		time.Sleep(time.Second)
+	}
 }
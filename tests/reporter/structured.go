@@ -0,0 +1,212 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+var (
+	// virtLauncherLogName and virtHandlerLogName match the per-node container log dumps the
+	// KubernetesReporter names with a "<nodeName>__" prefix, so the node they came from can be
+	// recovered without re-querying the cluster.
+	virtLauncherLogName = regexp.MustCompile(`^(?P<node>[^_]+)__virt-launcher.*\.log$`)
+	virtHandlerLogName  = regexp.MustCompile(`^(?P<node>[^_]+)__virt-handler.*\.log$`)
+
+	// resourceDumpName matches the aggregate resource YAMLs (one file per kind) the
+	// KubernetesReporter drops alongside the logs, e.g. "pods_20230101.yaml".
+	resourceDumpName = regexp.MustCompile(`^(?P<kind>pods|vmis|events|nodes)_.*\.yaml$`)
+)
+
+// ResourceArtifact points at a single kind of resource YAML (pods, VMIs, events, or node
+// conditions) the KubernetesReporter dumped alongside the failing spec.
+type ResourceArtifact struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
+// NodeLogPaths is the virt-launcher/virt-handler log files collected for a single node.
+type NodeLogPaths struct {
+	VirtLauncher []string `json:"virtLauncher,omitempty"`
+	VirtHandler  string   `json:"virtHandler,omitempty"`
+}
+
+// SpecArtifact is the structured, machine-readable record written for a single failing spec. It is
+// meant to be indexed by tooling (see hack/tools/kubevirt-artifacts) rather than read by a human, so
+// every field is plain data: no error values, no pointers into live cluster state.
+type SpecArtifact struct {
+	SpecPath       []string                `json:"specPath"`
+	Labels         []string                `json:"labels"`
+	FailureMessage string                  `json:"failureMessage,omitempty"`
+	StartTime      time.Time               `json:"startTime"`
+	EndTime        time.Time               `json:"endTime"`
+	Resources      []ResourceArtifact      `json:"resources,omitempty"`
+	NodeLogPaths   map[string]NodeLogPaths `json:"nodeLogPaths,omitempty"`
+	Checksum       string                  `json:"checksum"`
+}
+
+// StructuredKubernetesReporter writes one spec-<hash>-<attempt>.json manifest per failing spec into
+// artifactsPath, indexing whatever resource YAMLs and virt-launcher/virt-handler logs the
+// KubernetesReporter already dropped into that same directory for the spec. It is activated
+// independently of NewKubernetesReporter via the KUBEVIRT_STRUCTURED_ARTIFACTS environment
+// variable, and is capped by maxFails the same way the existing reporters are, so a systemic
+// failure doesn't flood artifacts with thousands of near-identical manifests.
+type StructuredKubernetesReporter struct {
+	artifactsPath string
+	maxFails      int
+
+	failures  int
+	startTime time.Time
+}
+
+// NewStructuredKubernetesReporter returns a StructuredKubernetesReporter that writes manifests under
+// artifactsPath, stopping once maxFails failing specs have been recorded.
+func NewStructuredKubernetesReporter(artifactsPath string, maxFails int) *StructuredKubernetesReporter {
+	return &StructuredKubernetesReporter{
+		artifactsPath: artifactsPath,
+		maxFails:      maxFails,
+	}
+}
+
+// JustBeforeEach records the spec's start time, used both for the manifest and to tell which log
+// files in artifactsPath were written during this spec.
+func (r *StructuredKubernetesReporter) JustBeforeEach(specReport types.SpecReport) {
+	r.startTime = time.Now()
+}
+
+// JustAfterEach writes a spec-<hash>-<attempt>.json manifest for specReport if it failed and the
+// reporter has not yet reached maxFails.
+func (r *StructuredKubernetesReporter) JustAfterEach(specReport types.SpecReport) {
+	if !specReport.Failed() {
+		return
+	}
+	if r.failures >= r.maxFails {
+		return
+	}
+	r.failures++
+
+	resources, nodeLogPaths := r.collectArtifacts()
+	artifact := SpecArtifact{
+		SpecPath:       specReport.ContainerHierarchyTexts,
+		Labels:         specReport.Labels(),
+		FailureMessage: specReport.FailureMessage(),
+		StartTime:      r.startTime,
+		EndTime:        time.Now(),
+		Resources:      resources,
+		NodeLogPaths:   nodeLogPaths,
+	}
+	artifact.Checksum = checksumOf(artifact)
+
+	if err := r.writeArtifact(artifact); err != nil {
+		fmt.Fprintf(os.Stderr, "structured reporter: failed to write artifact: %v\n", err)
+	}
+}
+
+// collectArtifacts walks artifactsPath for everything the KubernetesReporter wrote during this
+// spec's run: the resource YAML dumps (pods, VMIs, events, node conditions) and the per-node
+// virt-launcher/virt-handler logs.
+func (r *StructuredKubernetesReporter) collectArtifacts() ([]ResourceArtifact, map[string]NodeLogPaths) {
+	var resources []ResourceArtifact
+	nodeLogPaths := map[string]NodeLogPaths{}
+
+	_ = filepath.Walk(r.artifactsPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info == nil || info.IsDir() {
+			return nil
+		}
+		if info.ModTime().Before(r.startTime) {
+			return nil
+		}
+		name := info.Name()
+
+		if match := resourceDumpName.FindStringSubmatch(name); match != nil {
+			resources = append(resources, ResourceArtifact{Kind: resourceKindOf(match[1]), Path: path})
+			return nil
+		}
+		if match := virtLauncherLogName.FindStringSubmatch(name); match != nil {
+			node := nodeLogPaths[match[1]]
+			node.VirtLauncher = append(node.VirtLauncher, path)
+			nodeLogPaths[match[1]] = node
+			return nil
+		}
+		if match := virtHandlerLogName.FindStringSubmatch(name); match != nil {
+			node := nodeLogPaths[match[1]]
+			node.VirtHandler = path
+			nodeLogPaths[match[1]] = node
+		}
+		return nil
+	})
+
+	return resources, nodeLogPaths
+}
+
+// resourceKindOf maps a resource dump's file-name prefix to the kind it represents.
+func resourceKindOf(prefix string) string {
+	switch prefix {
+	case "pods":
+		return "Pod"
+	case "vmis":
+		return "VirtualMachineInstance"
+	case "events":
+		return "Event"
+	case "nodes":
+		return "NodeCondition"
+	default:
+		return prefix
+	}
+}
+
+// writeArtifact writes artifact as spec-<hash>-<attempt>.json, where attempt is r.failures at the
+// time of the call. The attempt suffix keeps repeated failures of the same spec (Ginkgo retries, or
+// a flaky spec failing more than once in a run) from overwriting each other's manifest, since those
+// repeat failures are exactly what a flake classifier needs to see.
+func (r *StructuredKubernetesReporter) writeArtifact(artifact SpecArtifact) error {
+	if err := os.MkdirAll(r.artifactsPath, 0775); err != nil {
+		return err
+	}
+
+	raw, err := json.MarshalIndent(artifact, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	specHash := fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(artifact.SpecPath, "/"))))[:12]
+	manifestPath := filepath.Join(r.artifactsPath, fmt.Sprintf("spec-%s-%d.json", specHash, r.failures))
+	return os.WriteFile(manifestPath, raw, 0644)
+}
+
+// checksumOf returns a hex SHA-256 content checksum of artifact, ignoring the Checksum field itself, so
+// downstream tooling can detect a manifest that was truncated or corrupted in transit.
+func checksumOf(artifact SpecArtifact) string {
+	artifact.Checksum = ""
+	raw, err := json.Marshal(artifact)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(raw))
+}
@@ -0,0 +1,181 @@
+/*
+ * This file is part of the KubeVirt project
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ * Copyright 2023 Red Hat, Inc.
+ *
+ */
+
+package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestResourceKindOf(t *testing.T) {
+	cases := map[string]string{
+		"pods":        "Pod",
+		"vmis":        "VirtualMachineInstance",
+		"events":      "Event",
+		"nodes":       "NodeCondition",
+		"unknownKind": "unknownKind",
+	}
+
+	for prefix, want := range cases {
+		if got := resourceKindOf(prefix); got != want {
+			t.Errorf("resourceKindOf(%q) = %q, want %q", prefix, got, want)
+		}
+	}
+}
+
+func TestResourceDumpNameMatches(t *testing.T) {
+	cases := map[string]bool{
+		"pods_20230101.yaml":   true,
+		"vmis_20230101.yaml":   true,
+		"events_20230101.yaml": true,
+		"nodes_20230101.yaml":  true,
+		"pods.yaml":            false,
+		"virt-launcher.log":    false,
+		"unrelated.yaml":       false,
+	}
+
+	for name, want := range cases {
+		if got := resourceDumpName.MatchString(name); got != want {
+			t.Errorf("resourceDumpName.MatchString(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestVirtLauncherLogNameMatches(t *testing.T) {
+	cases := map[string]bool{
+		"node01__virt-launcher-testvmi.log": true,
+		"node01__virt-handler.log":          false,
+		"virt-launcher-testvmi.log":         false,
+		"node01__virt-launcher.txt":         false,
+	}
+
+	for name, want := range cases {
+		if got := virtLauncherLogName.MatchString(name); got != want {
+			t.Errorf("virtLauncherLogName.MatchString(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestVirtHandlerLogNameMatches(t *testing.T) {
+	cases := map[string]bool{
+		"node01__virt-handler.log":          true,
+		"node01__virt-launcher-testvmi.log": false,
+		"virt-handler.log":                  false,
+	}
+
+	for name, want := range cases {
+		if got := virtHandlerLogName.MatchString(name); got != want {
+			t.Errorf("virtHandlerLogName.MatchString(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestChecksumOf_DeterministicAndIgnoresExistingChecksum(t *testing.T) {
+	artifact := SpecArtifact{SpecPath: []string{"Services", "NodePort"}, FailureMessage: "boom"}
+
+	first := checksumOf(artifact)
+	second := checksumOf(artifact)
+	if first == "" {
+		t.Fatal("expected a non-empty checksum")
+	}
+	if first != second {
+		t.Errorf("checksumOf is not deterministic: %q != %q", first, second)
+	}
+
+	artifact.Checksum = "stale-checksum-from-a-previous-write"
+	if got := checksumOf(artifact); got != first {
+		t.Errorf("checksumOf(%v) = %q, want %q (the Checksum field must not affect its own value)", artifact, got, first)
+	}
+
+	artifact.FailureMessage = "a different failure"
+	if got := checksumOf(artifact); got == first {
+		t.Error("expected the checksum to change when the artifact content changes")
+	}
+}
+
+func TestCollectArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	startTime := time.Now()
+
+	writeAt := func(name string, mtime time.Time) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("contents"), 0644); err != nil {
+			t.Fatalf("writing %s: %v", path, err)
+		}
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("setting mtime for %s: %v", path, err)
+		}
+	}
+
+	writeAt("pods_001.yaml", startTime.Add(time.Second))
+	writeAt("node01__virt-launcher-testvmi.log", startTime.Add(time.Second))
+	writeAt("node01__virt-handler.log", startTime.Add(time.Second))
+	writeAt("node02__virt-launcher-othervmi.log", startTime.Add(time.Second))
+	writeAt("pods_000.yaml", startTime.Add(-time.Hour)) // written before the spec started, must be excluded
+
+	r := &StructuredKubernetesReporter{artifactsPath: dir, startTime: startTime}
+	resources, nodeLogPaths := r.collectArtifacts()
+
+	if len(resources) != 1 || resources[0].Kind != "Pod" {
+		t.Errorf("expected a single Pod resource artifact, got %+v", resources)
+	}
+
+	node01, ok := nodeLogPaths["node01"]
+	if !ok {
+		t.Fatal("expected log paths for node01")
+	}
+	if len(node01.VirtLauncher) != 1 || node01.VirtHandler == "" {
+		t.Errorf("expected node01 to have one virt-launcher log and a virt-handler log, got %+v", node01)
+	}
+
+	node02, ok := nodeLogPaths["node02"]
+	if !ok {
+		t.Fatal("expected log paths for node02")
+	}
+	if len(node02.VirtLauncher) != 1 || node02.VirtHandler != "" {
+		t.Errorf("expected node02 to have only a virt-launcher log, got %+v", node02)
+	}
+}
+
+func TestWriteArtifact_AttemptDisambiguatesRepeatedFailures(t *testing.T) {
+	dir := t.TempDir()
+	r := &StructuredKubernetesReporter{artifactsPath: dir}
+	artifact := SpecArtifact{SpecPath: []string{"Services", "flaky spec"}}
+
+	r.failures = 1
+	if err := r.writeArtifact(artifact); err != nil {
+		t.Fatalf("writeArtifact (attempt 1): %v", err)
+	}
+
+	r.failures = 2
+	if err := r.writeArtifact(artifact); err != nil {
+		t.Fatalf("writeArtifact (attempt 2): %v", err)
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "spec-*.json"))
+	if err != nil {
+		t.Fatalf("globbing %s: %v", dir, err)
+	}
+	if len(matches) != 2 {
+		t.Errorf("expected the two attempts to produce two distinct manifests, got %v", matches)
+	}
+}
@@ -49,6 +49,7 @@ import (
 
 var afterSuiteReporters = []Reporter{}
 var k8sReporter *reporter.KubernetesReporter
+var structuredReporter *reporter.StructuredKubernetesReporter
 
 func TestTests(t *testing.T) {
 	flags.NormalizeFlags()
@@ -81,6 +82,10 @@ func TestTests(t *testing.T) {
 	k8sReporter = reporter.NewKubernetesReporter(artifactsPath, maxFails)
 	k8sReporter.Cleanup()
 
+	if os.Getenv("KUBEVIRT_STRUCTURED_ARTIFACTS") == "1" {
+		structuredReporter = reporter.NewStructuredKubernetesReporter(artifactsPath, maxFails)
+	}
+
 	vmsgeneratorutils.DockerPrefix = flags.KubeVirtUtilityRepoPrefix
 	vmsgeneratorutils.DockerTag = flags.KubeVirtVersionTag
 
@@ -114,8 +119,14 @@ var _ = ReportAfterSuite("TestTests", func(report Report) {
 
 var _ = ReportBeforeEach(func(specReport SpecReport) {
 	k8sReporter.JustBeforeEach(CurrentSpecReport())
+	if structuredReporter != nil {
+		structuredReporter.JustBeforeEach(CurrentSpecReport())
+	}
 })
 
 var _ = ReportAfterEach(func(specReport SpecReport) {
 	k8sReporter.JustAfterEach(CurrentSpecReport())
+	if structuredReporter != nil {
+		structuredReporter.JustAfterEach(CurrentSpecReport())
+	}
 })